@@ -0,0 +1,153 @@
+/*
+ This RiverCrossing project (https://github.com/FlorinTulba/RiverCrossing)
+ allows describing and solving River Crossing puzzles:
+  https://en.wikipedia.org/wiki/River_crossing_puzzle
+
+ (c) 2018-2025 Florin Tulba (florintulba@yahoo.com)
+*/
+
+// This file exposes /api/v1/solve and /api/v1/health, a JSON counterpart to
+// the HTML form served by editScenario, for scripts, CI and other services.
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/FlorinTulba/RiverCrossing/GoServer/internal/solverpool"
+)
+
+// apiSolveRequest is the body expected by POST /api/v1/solve.
+type apiSolveRequest struct {
+	Scenario    string `json:"scenario"`
+	Interactive bool   `json:"interactive"`
+	TimeoutMs   int    `json:"timeout_ms"`
+	Solver      string `json:"solver"` // name from GET /api/v1/solvers; empty picks the newest
+}
+
+// apiSolveResponse is the body returned by POST /api/v1/solve.
+type apiSolveResponse struct {
+	Solved      bool     `json:"solved"`
+	Steps       []string `json:"steps"`
+	ExitStatus  int      `json:"exit_status"`
+	Diagnostics string   `json:"diagnostics"`
+}
+
+// solverSteps splits the solver's raw output into its non-empty, trimmed
+// lines, the closest structured representation available without changing
+// the solver's own output format.
+func solverSteps(out string) []string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	steps := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			steps = append(steps, line)
+		}
+	}
+	return steps
+}
+
+// exitStatusOf reports ps's exit code, or -1 if the child never exited.
+func exitStatusOf(ps *os.ProcessState) int {
+	if ps == nil {
+		return -1
+	}
+	return ps.ExitCode()
+}
+
+// apiSolve handles POST /api/v1/solve: it runs the solver against the posted
+// scenario and returns a structured, JSON view of the outcome instead of the
+// HTML-escaped text editScenario renders.
+func apiSolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req apiSolveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	solver, err := resolveSolver(req.Solver)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timeout := *defaultSolverTimeout
+	if req.TimeoutMs > 0 {
+		timeout = time.Duration(req.TimeoutMs) * time.Millisecond
+	}
+
+	res := solverPool.Run(r.Context(), solverpool.Job{
+		SolverPath:  solver.Path,
+		Input:       strings.NewReader(req.Scenario),
+		Interactive: req.Interactive,
+		Timeout:     timeout,
+	})
+	out, solved, err := interpretSolverResult(res, timeout)
+
+	diagnostics := out
+	if err != nil {
+		diagnostics = err.Error() + "\n" + out
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(apiSolveResponse{
+		Solved:      solved,
+		Steps:       solverSteps(out),
+		ExitStatus:  exitStatusOf(res.ProcessState),
+		Diagnostics: diagnostics,
+	})
+}
+
+// apiHealthResponse is the body returned by GET /api/v1/health.
+type apiHealthResponse struct {
+	Status        string `json:"status"`
+	SolverName    string `json:"solver_name"`
+	SolverPath    string `json:"solver_path"`
+	SolverModTime string `json:"solver_mod_time,omitempty"`
+	Compiler      string `json:"compiler"`
+}
+
+// apiHealth handles GET /api/v1/health: it runs the same "{}" smoke test
+// main() runs at startup against the solver named by "?solver=" (or the
+// newest one), and reports that solver's name, path, mtime and compiler
+// flavor alongside the outcome.
+func apiHealth(w http.ResponseWriter, r *http.Request) {
+	solver, err := resolveSolver(r.FormValue("solver"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := apiHealthResponse{
+		SolverName: solver.Name,
+		SolverPath: solver.Path,
+		Compiler:   solver.Compiler(),
+		Status:     "ok",
+	}
+	if !solver.ModTime.IsZero() {
+		resp.SolverModTime = solver.ModTime.Format(time.RFC3339)
+	}
+
+	if _, _, err := callSolver(r.Context(), strings.NewReader("{}"), false,
+		*defaultSolverTimeout, solver); err != nil {
+		resp.Status = "error: " + err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// apiSolvers handles GET /api/v1/solvers: it lists every solver the server
+// discovered at startup, so a client can pick one by name.
+func apiSolvers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(solvers)
+}
@@ -0,0 +1,54 @@
+/*
+ This RiverCrossing project (https://github.com/FlorinTulba/RiverCrossing)
+ allows describing and solving River Crossing puzzles:
+  https://en.wikipedia.org/wiki/River_crossing_puzzle
+
+ (c) 2018-2025 Florin Tulba (florintulba@yahoo.com)
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// galleryEntry mirrors the shape served from web/gallery/<puzzle>.json.
+type galleryEntry struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Data  string `json:"data"`
+}
+
+// TestGalleryEntriesHoldSolverInput checks that every seeded gallery entry's
+// Data is well-formed solver input (a JSON scenario, not just a prose
+// description), so it can be loaded straight into the editor and solved.
+func TestGalleryEntriesHoldSolverInput(t *testing.T) {
+	entries, err := filepath.Glob("../../web/gallery/*.json")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("no gallery entries found")
+	}
+
+	for _, path := range entries {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile(%s): %v", path, err)
+		}
+
+		var entry galleryEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", path, err)
+		}
+		if entry.ID == "" || entry.Title == "" {
+			t.Fatalf("%s: id and title are required", path)
+		}
+		if !json.Valid([]byte(entry.Data)) {
+			t.Errorf("%s: data is not valid JSON scenario input: %q", path, entry.Data)
+		}
+	}
+}
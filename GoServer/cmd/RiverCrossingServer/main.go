@@ -22,10 +22,17 @@ The executable may be launched from <ProjectFolder>, like:
 
 <ProjectFolder>/startWebServer.(sh|bat)
 
-The paths of the actual RiverCrossing[.exe] solvers are:
-<ProjectFolder>/x(32|64|_arm)/(msvc|[clan]g++)/Release/RiverCrossing[.exe]
+The server discovers the available solver binaries at startup, combining:
 
-If there are more solvers available, the server selects the most recent one.
+  - a filesystem scan for <ProjectFolder>/x(32|64|_arm)/(msvc|[clan]g++)/Release/RiverCrossing[.exe]
+  - an optional <ProjectFolder>/solvers.json (or -solvers-config) listing further
+    {"name", "path", "arch", "version"} entries
+  - the RIVERCROSSING_SOLVER / RIVERCROSSING_SOLVER_DIR environment variables
+
+By default a request runs against the most recently modified solver found;
+passing a "solver" form field (or JSON field) picks a specific one by name,
+e.g. ?solver=clang++-x64. GET http://localhost:8080/api/v1/solvers lists every
+solver the server knows about.
 
 MSYS/Cygwin-compiled solvers can serve only to servers launched exactly from the
 same environment, to ensure the PATH contains all necessary folders.
@@ -33,37 +40,80 @@ same environment, to ensure the PATH contains all necessary folders.
 The link to use is:
 
 http://localhost:8080/RiverCrossing
+
+For a live trace of the solver instead of waiting for it to finish, post the
+same form fields to:
+
+http://localhost:8080/RiverCrossing/stream
+
+which streams the solver's output as Server-Sent Events and closes the
+connection once the puzzle is solved (or fails). Closing the connection
+early (e.g. a Stop button) cancels the solver.
+
+Scripts, CI and other services that don't want HTML can instead use the
+JSON API:
+
+	POST http://localhost:8080/api/v1/solve
+		{"scenario": "...", "interactive": bool, "timeout_ms": int}
+		-> {"solved": bool, "steps": [...], "exit_status": int, "diagnostics": "..."}
+
+	GET  http://localhost:8080/api/v1/health
+		runs the same "{}" smoke test as startup and reports the selected
+		solver's path, mtime and compiler flavor
+
+A scenario typed into the editor no longer has to be re-typed after a
+refresh:
+
+	POST http://localhost:8080/scenarios       saves {"title", "scenarioData"},
+	                                            returning a short id and a
+	                                            share_url
+	GET  http://localhost:8080/scenarios       lists every saved scenario
+	GET  http://localhost:8080/scenarios/{id}  fetches one by id
+	GET  http://localhost:8080/RiverCrossing?scenario={id}
+	                                            loads it into the editor
+
+A gallery of classic puzzles (wolf/goat/cabbage, missionaries & cannibals,
+jealous husbands, bridge & torch) is served from:
+
+	http://localhost:8080/gallery/<puzzle>.json
+
+Flags:
+
+	-max-concurrent  caps how many solver processes may run at once
+	                 (default: runtime.NumCPU())
+	-solver-timeout  default wall-clock deadline for a solver run; a request
+	                 can override it with its own "timeoutMs" form field
+	-solvers-config  path to a solvers.json listing available solvers
+	                 (default: <ProjectFolder>/solvers.json)
 */
 package main
 
 import (
+	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"html/template"
 	"io"
 	"log"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
+
+	"github.com/FlorinTulba/RiverCrossing/GoServer/internal/scenariostore"
+	"github.com/FlorinTulba/RiverCrossing/GoServer/internal/solverpool"
+	"github.com/FlorinTulba/RiverCrossing/GoServer/internal/solverregistry"
 )
 
 var OS = runtime.GOOS
 var sep = string(os.PathSeparator)
 
-// Optional extension of the solver executable
-func solverExt() string {
-	if OS == "windows" {
-		return ".exe"
-	}
-	return ""
-}
-
 // One of x(_arm|64|32), as the solver is saved within <ProjectFolder>/x(_arm|64|32)/...
 func solverBaseDirName() string {
 	if runtime.GOARCH == "arm" {
@@ -99,52 +149,64 @@ var projectDir = projDir()
 // Folder containing css and js files, html templates, images and the web server
 var webFolder = projectDir + sep + "GoServer" + sep + "web"
 
-// path of the newest solver available; empty if none
-var solverPath string
-
 // html templates for the web server
 var templates *template.Template
 
-// Determines solverPath as the path of the newest solver available
-func selectSolver() string {
-	baseDir := projectDir + sep + theSolverBaseDirName + sep
-	solverSuffix := sep + "Release" + sep + "RiverCrossing" + solverExt()
-
-	relPath1 := baseDir + "g++" + solverSuffix
-	relPath2 := baseDir + "clang++" + solverSuffix
-	relPath3 := baseDir + "msvc" + solverSuffix
-
-	found := false
-
-	mt1 := time.Time{}
-	if fileInfo, err := os.Stat(relPath1); err == nil {
-		mt1 = fileInfo.ModTime()
-		found = true
+// Caps how many solver child processes may run at once; 0 means
+// runtime.NumCPU(). Overridable with -max-concurrent.
+var maxConcurrent = flag.Int("max-concurrent", 0,
+	"maximum number of solver processes running at once (default: runtime.NumCPU())")
+
+// Wall-clock deadline applied to a solver run when the request doesn't
+// specify its own. Overridable with -solver-timeout.
+var defaultSolverTimeout = flag.Duration("solver-timeout", 30*time.Second,
+	"default wall-clock deadline for a solver run")
+
+// Overrides the solvers.json path the ConfigRegistry reads.
+// Overridable with -solvers-config.
+var solversConfigPath = flag.String("solvers-config", "",
+	"path to a solvers.json listing available solvers "+
+		"(default: <ProjectFolder>/solvers.json)")
+
+// Pool of solver child processes, bounding concurrency and enforcing deadlines
+var solverPool *solverpool.Pool
+
+// Every solver discovered at startup, combining a filesystem scan, an
+// optional solvers.json config file, and the RIVERCROSSING_SOLVER[_DIR]
+// environment variables, in that increasing order of precedence.
+var solvers solverregistry.SolverList
+
+// defaultSolver is the solver a request gets when it doesn't name one
+// explicitly: the most recently modified entry in solvers.
+var defaultSolver solverregistry.Solver
+
+// discoverSolvers builds and merges the known Registry implementations.
+func discoverSolvers() solverregistry.SolverList {
+	configPath := *solversConfigPath
+	if configPath == "" {
+		configPath = projectDir + sep + "solvers.json"
 	}
 
-	mt2 := time.Time{}
-	if fileInfo, err := os.Stat(relPath2); err == nil {
-		mt2 = fileInfo.ModTime()
-		found = true
-	}
-
-	mt3 := time.Time{}
-	if fileInfo, err := os.Stat(relPath3); err == nil {
-		mt3 = fileInfo.ModTime()
-		found = true
-	}
-
-	if !found {
-		return ""
-	}
+	return solverregistry.Merge(
+		solverregistry.FSRegistry{ProjectDir: projectDir, BaseDirName: theSolverBaseDirName, Sep: sep},
+		solverregistry.ConfigRegistry{ConfigPath: configPath},
+		solverregistry.EnvRegistry{Sep: sep},
+	)
+}
 
-	if mt1.After(mt2) && mt1.After(mt3) {
-		return relPath1
+// resolveSolver picks the solver named by the client (the "solver" form
+// field or JSON field), or falls back to defaultSolver when name is empty.
+func resolveSolver(name string) (solverregistry.Solver, error) {
+	if name == "" {
+		if defaultSolver.Path == "" {
+			return solverregistry.Solver{}, errors.New("no solver available")
+		}
+		return defaultSolver, nil
 	}
-	if mt2.After(mt1) && mt2.After(mt3) {
-		return relPath2
+	if s, ok := solvers.Find(name); ok {
+		return s, nil
 	}
-	return relPath3
+	return solverregistry.Solver{}, fmt.Errorf("unknown solver %q", name)
 }
 
 // What to do when the call to the solver goes wrong
@@ -232,32 +294,38 @@ For these exit codes there is clear feedback directly from the solver.
 Output variable err is nil for these cases only.
 Any other case should provide a non-nil err.
 */
-func callSolver(inputReader io.Reader, interactive bool) (out string, solved bool, err error) {
-	out, solved, err = "", true, nil
-
-	cmdArg := "interactive"
-	if !interactive {
-		cmdArg = ""
-	}
-
-	cmd := exec.Command(solverPath, cmdArg)
-	cmd.Stdin = inputReader
+func callSolver(ctx context.Context, inputReader io.Reader, interactive bool,
+	timeout time.Duration, solver solverregistry.Solver) (out string, solved bool, err error) {
+	res := solverPool.Run(ctx, solverpool.Job{
+		SolverPath:  solver.Path,
+		Input:       inputReader,
+		Interactive: interactive,
+		Timeout:     timeout,
+	})
+	return interpretSolverResult(res, timeout)
+}
 
-	var combinedOutput []byte
-	combinedOutput, err = cmd.CombinedOutput() // Stderr has collected errors
+// interpretSolverResult turns a raw solverpool.Result into the (out, solved,
+// err) triple callers render, regardless of whether the run was buffered
+// (callSolver) or streamed (streamSolver).
+func interpretSolverResult(res solverpool.Result, timeout time.Duration) (out string, solved bool, err error) {
+	out, solved, err = res.Out, true, res.Err
 
-	if combinedOutput != nil {
-		out += string(combinedOutput)
+	if res.TimedOut {
+		solved = false
+		out += fmt.Sprintf("\nSolver timed out after %s", timeout)
+		err = nil
+		return
 	}
 
-	hasProcessState := cmd.ProcessState != nil
+	hasProcessState := res.ProcessState != nil
 
 	// WaitStatus is either uint32 or a struct containing uint32
 	var waitStatus syscall.WaitStatus // zero-initialized in both cases
 	didExit := false
 	exitStatus := 0
 	if hasProcessState {
-		waitStatus = cmd.ProcessState.Sys().(syscall.WaitStatus)
+		waitStatus = res.ProcessState.Sys().(syscall.WaitStatus)
 		if waitStatus.Exited() {
 			didExit = true
 			exitStatus = waitStatus.ExitStatus()
@@ -267,7 +335,7 @@ func callSolver(inputReader io.Reader, interactive bool) (out string, solved boo
 	if err == nil {
 		// hasProcessState must be true
 		if !hasProcessState {
-			msg := "When err from cmd.CombinedOutput() is nil, cmd.ProcessState cannot be nil!"
+			msg := "When err from the solver run is nil, its ProcessState cannot be nil!"
 			out += msg
 			solved = false
 			err = errors.New(msg)
@@ -326,8 +394,14 @@ func post(r *http.Request) (tmpl, out string) {
 	input := r.FormValue("scenarioData")
 	inputReader := strings.NewReader(input)
 	interactive := r.FormValue("interactiveSol") == "on"
+
+	solver, err := resolveSolver(r.FormValue("solver"))
+	if err != nil {
+		return "index", err.Error()
+	}
+
 	var solved bool
-	out, solved, _ = callSolver(inputReader, interactive)
+	out, solved, _ = callSolver(r.Context(), inputReader, interactive, requestTimeout(r), solver)
 
 	if solved {
 		if interactive {
@@ -342,6 +416,16 @@ func post(r *http.Request) (tmpl, out string) {
 	return
 }
 
+// requestTimeout returns the per-request wall-clock deadline for the solver
+// run: the "timeoutMs" form field when present and valid, otherwise
+// *defaultSolverTimeout.
+func requestTimeout(r *http.Request) time.Duration {
+	if ms, err := strconv.Atoi(r.FormValue("timeoutMs")); err == nil && ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return *defaultSolverTimeout
+}
+
 func renderTemplate(w http.ResponseWriter, tmpl, replacement string) {
 	err := templates.ExecuteTemplate(w, tmpl+".html",
 		replacement)
@@ -353,6 +437,12 @@ func renderTemplate(w http.ResponseWriter, tmpl, replacement string) {
 func editScenario(w http.ResponseWriter, r *http.Request) {
 	input := r.FormValue("scenarioData")
 	if len(input) == 0 { // scenarioData is empty at start
+		if id := r.FormValue("scenario"); id != "" { // following a share link
+			if scenario, err := scenarioStore.Get(id); err == nil {
+				renderTemplate(w, "index", scenario.Data)
+				return
+			}
+		}
 		renderTemplate(w, "index", "") // just display the index template
 		return
 	}
@@ -365,33 +455,135 @@ func provideFile(w http.ResponseWriter, r *http.Request) {
 	http.ServeFile(w, r, webFolder+r.URL.Path)
 }
 
+// writeSSEData writes data as one SSE "data:" event, splitting it onto
+// several "data:" lines when it spans multiple lines, as the SSE spec
+// requires, then flushes it to the client immediately.
+func writeSSEData(w io.Writer, flusher http.Flusher, data string) {
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+	flusher.Flush()
+}
+
+// writeSSEEvent is writeSSEData, but labelled with a named event type.
+func writeSSEEvent(w io.Writer, flusher http.Flusher, event, data string) {
+	fmt.Fprintf(w, "event: %s\n", event)
+	writeSSEData(w, flusher, data)
+}
+
+// streamSolver serves /RiverCrossing/stream: it renders the header
+// immediately, then flushes each line of solver output to the browser as a
+// Server-Sent Event as soon as it is produced, and finally emits an
+// "event: done" carrying the exit status. Closing the connection (e.g. the
+// user hits Stop) cancels r.Context(), which - via the pool - terminates the
+// still-running solver.
+func streamSolver(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	input := r.FormValue("scenarioData")
+	interactive := r.FormValue("interactiveSol") == "on"
+	timeout := requestTimeout(r)
+
+	solver, err := resolveSolver(r.FormValue("solver"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	headerTmpl, footerTmpl := "listedSolutionHeader", "listedSolutionFooter"
+	if interactive {
+		headerTmpl, footerTmpl = "interactiveSolutionHeader", "interactiveSolutionFooter"
+	}
+
+	if err := templates.ExecuteTemplate(w, headerTmpl+".html", nil); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	flusher.Flush()
+
+	res := solverPool.RunStreaming(r.Context(), solverpool.Job{
+		SolverPath:  solver.Path,
+		Input:       strings.NewReader(input),
+		Interactive: interactive,
+		Timeout:     timeout,
+	}, func(line solverpool.Line) {
+		writeSSEData(w, flusher, adaptAsHtmlOutput(line.Text))
+	})
+
+	_, solved, err := interpretSolverResult(res, timeout)
+	status := "solved"
+	switch {
+	case err != nil:
+		status = "error: " + err.Error()
+	case !solved:
+		status = "failed"
+	}
+	writeSSEEvent(w, flusher, "done", status)
+
+	templates.ExecuteTemplate(w, footerTmpl+".html", nil)
+	flusher.Flush()
+}
+
 func main() {
+	flag.Parse()
+
 	if projectDir == "" {
 		fmt.Fprintln(os.Stderr, "Couldn't find the RiverCrossing project folder!")
 		return
 	}
-	if solverPath = selectSolver(); solverPath == "" {
+	solvers = discoverSolvers()
+	var ok bool
+	if defaultSolver, ok = solvers.Newest(); !ok {
 		fmt.Fprintln(os.Stderr, "Couldn't find a solver to launch!")
 		return
 	}
 
-	fmt.Println("Selected the solver:", solverPath)
+	fmt.Println("Selected the solver:", defaultSolver.Path)
+
+	solverPool = solverpool.New(*maxConcurrent)
 
 	// Make a test call to the solver before offering its services
-	if out, _, err := callSolver(strings.NewReader("{}"), false); err != nil {
+	if out, _, err := callSolver(context.Background(), strings.NewReader("{}"), false,
+		*defaultSolverTimeout, defaultSolver); err != nil {
 		fmt.Fprintln(os.Stderr, out)
 		return
 	}
 
+	var err error
+	if scenarioStore, err = scenariostore.New(webFolder + sep + "scenarios"); err != nil {
+		fmt.Fprintln(os.Stderr, "Couldn't create the scenario store:", err)
+		return
+	}
+
 	templatesFolder := webFolder + sep
 	templates = template.Must(template.ParseFiles(
 		templatesFolder+"index.html",
 		templatesFolder+"interactiveSolution.html",
-		templatesFolder+"listedSolution.html"))
+		templatesFolder+"listedSolution.html",
+		templatesFolder+"interactiveSolutionHeader.html",
+		templatesFolder+"interactiveSolutionFooter.html",
+		templatesFolder+"listedSolutionHeader.html",
+		templatesFolder+"listedSolutionFooter.html"))
 	http.HandleFunc("/RiverCrossing", editScenario)
+	http.HandleFunc("/RiverCrossing/stream", streamSolver)
+	http.HandleFunc("/api/v1/solve", apiSolve)
+	http.HandleFunc("/api/v1/health", apiHealth)
+	http.HandleFunc("/api/v1/solvers", apiSolvers)
+	http.HandleFunc("/scenarios", scenarios)
+	http.HandleFunc("/scenarios/", scenarioByID)
 	http.HandleFunc("/css/", provideFile)
 	http.HandleFunc("/js/", provideFile)
 	http.HandleFunc("/images/", provideFile)
+	http.HandleFunc("/gallery/", provideFile)
 
 	fmt.Print("Starting the ")
 	if OS != "windows" {
@@ -0,0 +1,98 @@
+/*
+ This RiverCrossing project (https://github.com/FlorinTulba/RiverCrossing)
+ allows describing and solving River Crossing puzzles:
+  https://en.wikipedia.org/wiki/River_crossing_puzzle
+
+ (c) 2018-2025 Florin Tulba (florintulba@yahoo.com)
+*/
+
+// This file exposes /scenarios (save and list) and /scenarios/{id} (fetch),
+// backed by scenarioStore, so a scenario typed into the editor survives a
+// refresh and can be shared with a short link.
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/FlorinTulba/RiverCrossing/GoServer/internal/scenariostore"
+)
+
+// scenarioStore persists user scenarios under webFolder/scenarios.
+var scenarioStore *scenariostore.Store
+
+// saveScenarioResponse is saveScenario's response: the saved scenario, plus
+// the link a "share" button can hand out directly.
+type saveScenarioResponse struct {
+	scenariostore.Scenario
+	ShareURL string `json:"share_url"`
+}
+
+// scenarios handles /scenarios: POST saves a new scenario, GET lists every
+// saved one.
+func scenarios(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		saveScenario(w, r)
+	case http.MethodGet:
+		listScenarios(w, r)
+	default:
+		http.Error(w, "GET or POST required", http.StatusMethodNotAllowed)
+	}
+}
+
+func saveScenario(w http.ResponseWriter, r *http.Request) {
+	data := r.FormValue("scenarioData")
+	if data == "" {
+		http.Error(w, "scenarioData is required", http.StatusBadRequest)
+		return
+	}
+
+	scenario, err := scenarioStore.Save(r.FormValue("title"), data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saveScenarioResponse{
+		Scenario: scenario,
+		ShareURL: "http://" + r.Host + "/RiverCrossing?scenario=" + scenario.ID,
+	})
+}
+
+func listScenarios(w http.ResponseWriter, r *http.Request) {
+	savedScenarios, err := scenarioStore.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(savedScenarios)
+}
+
+// scenarioByID handles GET /scenarios/{id}: it fetches one saved scenario by
+// its short ID.
+func scenarioByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "GET required", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/scenarios/")
+	if id == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	scenario, err := scenarioStore.Get(id)
+	if err != nil {
+		http.Error(w, "scenario not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scenario)
+}
@@ -0,0 +1,133 @@
+/*
+ This RiverCrossing project (https://github.com/FlorinTulba/RiverCrossing)
+ allows describing and solving River Crossing puzzles:
+  https://en.wikipedia.org/wiki/River_crossing_puzzle
+
+ (c) 2018-2025 Florin Tulba (florintulba@yahoo.com)
+*/
+
+// Package scenariostore saves and retrieves user scenarios, so a puzzle
+// typed into the editor survives a refresh and can be shared with a short
+// link, instead of being lost the moment the page is closed. Each scenario
+// is kept as one JSON file under a directory - no database required.
+package scenariostore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Scenario is a saved puzzle, along with the short ID used to fetch or share
+// it.
+type Scenario struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Data      string    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Store persists Scenarios as one JSON file per scenario under Dir.
+type Store struct {
+	Dir string
+}
+
+// New returns a Store rooted at dir, creating it if it doesn't exist yet.
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{Dir: dir}, nil
+}
+
+// newID generates a short, URL-safe scenario ID.
+func newID() (string, error) {
+	raw := make([]byte, 4)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// idPattern matches the shape newID generates: 4 random bytes, hex-encoded.
+// Get rejects anything else, since an id can arrive straight from an
+// untrusted request parameter (e.g. editScenario's "scenario" form field),
+// and filepath.Join alone won't strip ".." segments out of it.
+var idPattern = regexp.MustCompile(`^[0-9a-f]{8}$`)
+
+func (s *Store) path(id string) (string, error) {
+	if !idPattern.MatchString(id) {
+		return "", fmt.Errorf("invalid scenario id %q", id)
+	}
+	return filepath.Join(s.Dir, id+".json"), nil
+}
+
+// Save assigns a new ID to (title, data) and persists it.
+func (s *Store) Save(title, data string) (Scenario, error) {
+	id, err := newID()
+	if err != nil {
+		return Scenario{}, err
+	}
+
+	scenario := Scenario{ID: id, Title: title, Data: data, CreatedAt: time.Now()}
+
+	encoded, err := json.Marshal(scenario)
+	if err != nil {
+		return Scenario{}, err
+	}
+	path, err := s.path(id)
+	if err != nil {
+		return Scenario{}, err
+	}
+	if err := os.WriteFile(path, encoded, 0o644); err != nil {
+		return Scenario{}, err
+	}
+	return scenario, nil
+}
+
+// Get loads the scenario with the given ID.
+func (s *Store) Get(id string) (Scenario, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return Scenario{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, err
+	}
+	var scenario Scenario
+	err = json.Unmarshal(data, &scenario)
+	return scenario, err
+}
+
+// List returns every saved scenario, newest first.
+func (s *Store) List() ([]Scenario, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	scenarios := make([]Scenario, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		id := entry.Name()[:len(entry.Name())-len(".json")]
+		scenario, err := s.Get(id)
+		if err != nil {
+			continue
+		}
+		scenarios = append(scenarios, scenario)
+	}
+
+	sort.Slice(scenarios, func(i, j int) bool {
+		return scenarios[i].CreatedAt.After(scenarios[j].CreatedAt)
+	})
+	return scenarios, nil
+}
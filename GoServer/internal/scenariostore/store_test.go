@@ -0,0 +1,49 @@
+/*
+ This RiverCrossing project (https://github.com/FlorinTulba/RiverCrossing)
+ allows describing and solving River Crossing puzzles:
+  https://en.wikipedia.org/wiki/River_crossing_puzzle
+
+ (c) 2018-2025 Florin Tulba (florintulba@yahoo.com)
+*/
+
+package scenariostore
+
+import "testing"
+
+func TestSaveGetRoundTrip(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	saved, err := s.Save("Wolf, Goat and Cabbage", `{"entities":["wolf"]}`)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := s.Get(saved.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.ID != saved.ID || got.Title != saved.Title || got.Data != saved.Data || !got.CreatedAt.Equal(saved.CreatedAt) {
+		t.Fatalf("Get returned %+v, want %+v", got, saved)
+	}
+}
+
+func TestGetRejectsPathTraversal(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for _, id := range []string{
+		"../../../../etc/passwd",
+		"../secret",
+		"too-long-to-be-an-id",
+		"",
+	} {
+		if _, err := s.Get(id); err == nil {
+			t.Errorf("Get(%q): expected an error, got none", id)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ This RiverCrossing project (https://github.com/FlorinTulba/RiverCrossing)
+ allows describing and solving River Crossing puzzles:
+  https://en.wikipedia.org/wiki/River_crossing_puzzle
+
+ (c) 2018-2025 Florin Tulba (florintulba@yahoo.com)
+*/
+
+// Package solverpool bounds how many RiverCrossing solver child processes may
+// run at once, queues the rest and ties each run to a caller-supplied
+// context, so a burst of requests or a misbehaving puzzle can no longer fork
+// unboundedly or hang the server.
+package solverpool
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Job describes a single solver invocation to schedule within the Pool.
+type Job struct {
+	SolverPath  string        // path of the solver executable to run
+	Input       io.Reader     // piped to the solver's stdin
+	Interactive bool          // whether to pass the "interactive" argument
+	Timeout     time.Duration // wall-clock deadline for this job; 0 means no extra deadline
+}
+
+// Result gathers everything the caller needs to turn a finished (or
+// timed-out) run into a response.
+type Result struct {
+	Out          string
+	Err          error
+	TimedOut     bool
+	ProcessState *os.ProcessState // nil if the child never started
+}
+
+// Pool caps the number of concurrently running solver processes at
+// maxConcurrent; extra jobs block in Run until a slot frees up. A single Pool
+// is shared across every solver binary the server knows about - which one to
+// launch is decided per Job, via Job.SolverPath.
+type Pool struct {
+	sem chan struct{}
+
+	// WaitDelay bounds how long a solver is given to exit after being sent
+	// SIGTERM before Run escalates to SIGKILL.
+	WaitDelay time.Duration
+}
+
+// New creates a Pool that allows at most maxConcurrent solver processes to
+// run simultaneously. maxConcurrent <= 0 defaults to runtime.NumCPU().
+func New(maxConcurrent int) *Pool {
+	if maxConcurrent <= 0 {
+		maxConcurrent = runtime.NumCPU()
+	}
+	return &Pool{
+		sem:       make(chan struct{}, maxConcurrent),
+		WaitDelay: 5 * time.Second,
+	}
+}
+
+// acquire blocks until a pool slot is free or ctx is done, and derives the
+// context a single run should be bound to, applying job.Timeout if set.
+func (p *Pool) acquire(ctx context.Context, job Job) (runCtx context.Context, release func(), err error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	runCtx = ctx
+	cancel := func() {}
+	if job.Timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, job.Timeout)
+	}
+
+	return runCtx, func() { cancel(); <-p.sem }, nil
+}
+
+// newCmd builds the exec.Cmd for job, bound to runCtx, wired so that
+// cancelling runCtx sends SIGTERM and escalates to SIGKILL after p.WaitDelay.
+func (p *Pool) newCmd(runCtx context.Context, job Job) *exec.Cmd {
+	cmdArg := "interactive"
+	if !job.Interactive {
+		cmdArg = ""
+	}
+
+	cmd := exec.CommandContext(runCtx, job.SolverPath, cmdArg)
+	cmd.Stdin = job.Input
+	cmd.WaitDelay = p.WaitDelay
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	return cmd
+}
+
+// Run queues job until a slot is free, then executes it, bound to ctx.
+// Cancelling ctx (e.g. the client disconnected) or exceeding job.Timeout
+// terminates the child: first with SIGTERM, then - if it ignores that for
+// longer than p.WaitDelay - with SIGKILL.
+func (p *Pool) Run(ctx context.Context, job Job) Result {
+	runCtx, release, err := p.acquire(ctx, job)
+	if err != nil {
+		return Result{Err: err}
+	}
+	defer release()
+
+	cmd := p.newCmd(runCtx, job)
+	out, err := cmd.CombinedOutput()
+
+	return Result{
+		Out:          string(out),
+		Err:          err,
+		TimedOut:     runCtx.Err() == context.DeadlineExceeded,
+		ProcessState: cmd.ProcessState,
+	}
+}
+
+// Line is one line of solver output, tagged with its origin stream.
+type Line struct {
+	Text     string
+	IsStderr bool
+}
+
+// RunStreaming behaves like Run, except that onLine is invoked for every line
+// the solver writes to stdout or stderr as soon as it is produced, instead of
+// buffering the whole output until the solver exits. stdout and stderr are
+// drained by two goroutines, but both funnel their lines through a single
+// channel that RunStreaming itself consumes, so onLine is always called
+// serially from the calling goroutine - callers may write straight to a
+// shared destination (e.g. an http.ResponseWriter) without locking.
+func (p *Pool) RunStreaming(ctx context.Context, job Job, onLine func(Line)) Result {
+	runCtx, release, err := p.acquire(ctx, job)
+	if err != nil {
+		return Result{Err: err}
+	}
+	defer release()
+
+	cmd := p.newCmd(runCtx, job)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return Result{Err: err}
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	if err := cmd.Start(); err != nil {
+		return Result{Err: err}
+	}
+
+	lines := make(chan Line)
+	var drainers sync.WaitGroup
+	drainers.Add(2)
+	drain := func(r io.Reader, isStderr bool) {
+		defer drainers.Done()
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- Line{Text: scanner.Text(), IsStderr: isStderr}
+		}
+	}
+	go drain(stdout, false)
+	go drain(stderr, true)
+	go func() {
+		drainers.Wait()
+		close(lines)
+	}()
+	for line := range lines {
+		onLine(line)
+	}
+
+	err = cmd.Wait()
+
+	return Result{
+		Err:          err,
+		TimedOut:     runCtx.Err() == context.DeadlineExceeded,
+		ProcessState: cmd.ProcessState,
+	}
+}
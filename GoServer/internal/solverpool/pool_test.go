@@ -0,0 +1,136 @@
+/*
+ This RiverCrossing project (https://github.com/FlorinTulba/RiverCrossing)
+ allows describing and solving River Crossing puzzles:
+  https://en.wikipedia.org/wiki/River_crossing_puzzle
+
+ (c) 2018-2025 Florin Tulba (florintulba@yahoo.com)
+*/
+
+package solverpool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMain lets this test binary re-exec itself as a stand-in solver: run
+// with the lone argument "interactive" it sleeps well past any test timeout,
+// so Run/RunStreaming can be made to time out or be cancelled
+// deterministically; run with "" (a non-interactive Job) it prints one line
+// to stdout and one to stderr, then exits, mirroring a solver that solved
+// its puzzle.
+func TestMain(m *testing.M) {
+	if len(os.Args) == 2 && (os.Args[1] == "interactive" || os.Args[1] == "") {
+		runHelper(os.Args[1] == "interactive")
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func runHelper(interactive bool) {
+	if interactive {
+		time.Sleep(10 * time.Second)
+		return
+	}
+	fmt.Println("first line")
+	fmt.Fprintln(os.Stderr, "second line")
+}
+
+func TestRunSolves(t *testing.T) {
+	p := New(1)
+	res := p.Run(context.Background(), Job{SolverPath: os.Args[0]})
+	if res.Err != nil {
+		t.Fatalf("Run: %v", res.Err)
+	}
+	if res.TimedOut {
+		t.Fatal("TimedOut should be false for a job that exits on its own")
+	}
+	if !strings.Contains(res.Out, "first line") || !strings.Contains(res.Out, "second line") {
+		t.Fatalf("Out missing expected lines: %q", res.Out)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	p := New(1)
+	p.WaitDelay = 200 * time.Millisecond
+	res := p.Run(context.Background(), Job{
+		SolverPath:  os.Args[0],
+		Interactive: true,
+		Timeout:     50 * time.Millisecond,
+	})
+	if !res.TimedOut {
+		t.Fatalf("expected TimedOut, got %+v", res)
+	}
+}
+
+func TestRunCancellation(t *testing.T) {
+	p := New(1)
+	p.WaitDelay = 200 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	res := p.Run(ctx, Job{SolverPath: os.Args[0], Interactive: true})
+	if res.Err == nil {
+		t.Fatal("expected the cancelled child's exit error, got nil")
+	}
+}
+
+// TestPoolBoundsConcurrency checks that a maxConcurrent of 1 actually
+// serializes two jobs instead of running them side by side: the second
+// job's slot can't free up until the first's does, so it should finish
+// roughly jobTimeout later than the first, not alongside it.
+func TestPoolBoundsConcurrency(t *testing.T) {
+	p := New(1)
+	p.WaitDelay = 200 * time.Millisecond
+	jobTimeout := 100 * time.Millisecond
+
+	start := time.Now()
+	finished := make([]time.Duration, 2)
+	var wg sync.WaitGroup
+	for i := range finished {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p.Run(context.Background(), Job{
+				SolverPath:  os.Args[0],
+				Interactive: true,
+				Timeout:     jobTimeout,
+			})
+			finished[i] = time.Since(start)
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Slice(finished, func(i, j int) bool { return finished[i] < finished[j] })
+	if finished[1]-finished[0] < jobTimeout/2 {
+		t.Fatalf("expected the second job to be queued behind the first; finished at %v and %v",
+			finished[0], finished[1])
+	}
+}
+
+// TestRunStreamingConcurrentOnLine reproduces the exact pattern a caller like
+// streamSolver uses: onLine appends to a single, unsynchronized slice. If
+// stdout and stderr were ever drained by two goroutines both calling onLine
+// directly (instead of funnelling through one channel), `go test -race`
+// would flag this as a data race.
+func TestRunStreamingConcurrentOnLine(t *testing.T) {
+	p := New(1)
+	var lines []Line
+	res := p.RunStreaming(context.Background(), Job{SolverPath: os.Args[0]}, func(l Line) {
+		lines = append(lines, l)
+	})
+	if res.Err != nil {
+		t.Fatalf("RunStreaming: %v", res.Err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %+v", len(lines), lines)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ This RiverCrossing project (https://github.com/FlorinTulba/RiverCrossing)
+ allows describing and solving River Crossing puzzles:
+  https://en.wikipedia.org/wiki/River_crossing_puzzle
+
+ (c) 2018-2025 Florin Tulba (florintulba@yahoo.com)
+*/
+
+// Package solverregistry discovers the available RiverCrossing solver
+// binaries instead of hard-coding a single scan of <projDir>/x.../Release/...
+// and picking the newest one. Several Registry implementations can be
+// combined with Merge, so a config file or an environment variable can add
+// to, or override, what a filesystem scan finds.
+package solverregistry
+
+import (
+	"encoding/json"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Solver describes one discovered solver binary.
+type Solver struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Arch    string    `json:"arch,omitempty"`
+	Version string    `json:"version,omitempty"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// Registry discovers zero or more Solvers.
+type Registry interface {
+	Solvers() SolverList
+}
+
+// SolverList is the result of a Registry scan, or of Merge-ing several.
+type SolverList []Solver
+
+// Find returns the solver with the given name, if any.
+func (l SolverList) Find(name string) (Solver, bool) {
+	for _, s := range l {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Solver{}, false
+}
+
+// Compiler guesses s's compiler flavor from its Path, the same way
+// FSRegistry's own Name already encodes it, so a ConfigRegistry or
+// EnvRegistry entry (whose Name carries no such information) can still
+// report one when its Path happens to mention a known compiler.
+func (s Solver) Compiler() string {
+	// Checked longest-first: "clang++" also contains "g++", so a
+	// shortest-first scan would misreport every clang++ path as g++.
+	best := ""
+	for _, compiler := range knownCompilers {
+		if strings.Contains(s.Path, compiler) && len(compiler) > len(best) {
+			best = compiler
+		}
+	}
+	if best == "" {
+		return "unknown"
+	}
+	return best
+}
+
+// Newest returns the most recently modified solver, if any.
+func (l SolverList) Newest() (Solver, bool) {
+	var newest Solver
+	found := false
+	for _, s := range l {
+		if !found || s.ModTime.After(newest.ModTime) {
+			newest = s
+			found = true
+		}
+	}
+	return newest, found
+}
+
+// Merge concatenates the Solvers of every registry, in order; a later
+// registry's entry overrides an earlier one's of the same Name, so e.g. an
+// EnvRegistry can shadow a solvers.json entry, which in turn can shadow one
+// the filesystem scan found.
+func Merge(registries ...Registry) SolverList {
+	byName := map[string]Solver{}
+	var order []string
+	for _, reg := range registries {
+		for _, s := range reg.Solvers() {
+			if _, seen := byName[s.Name]; !seen {
+				order = append(order, s.Name)
+			}
+			byName[s.Name] = s
+		}
+	}
+
+	out := make(SolverList, 0, len(order))
+	for _, name := range order {
+		out = append(out, byName[name])
+	}
+	return out
+}
+
+// solverExt is the optional extension of the solver executable.
+func solverExt() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}
+
+// FSRegistry reproduces the project's original discovery logic: it looks for
+// a RiverCrossing[.exe] built with each of the known compilers under
+// <ProjectDir>/<BaseDirName>/<compiler>/Release/.
+type FSRegistry struct {
+	ProjectDir  string
+	BaseDirName string // one of x(_arm|64|32)
+	Sep         string
+}
+
+var knownCompilers = [...]string{"g++", "clang++", "msvc"}
+
+// Solvers implements Registry.
+func (r FSRegistry) Solvers() SolverList {
+	baseDir := r.ProjectDir + r.Sep + r.BaseDirName + r.Sep
+	var out SolverList
+	for _, compiler := range knownCompilers {
+		path := baseDir + compiler + r.Sep + "Release" + r.Sep + "RiverCrossing" + solverExt()
+		fileInfo, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		out = append(out, Solver{
+			Name:    compiler + "-" + r.BaseDirName,
+			Path:    path,
+			Arch:    r.BaseDirName,
+			ModTime: fileInfo.ModTime(),
+		})
+	}
+	return out
+}
+
+// ConfigRegistry loads solver entries from a JSON file holding an array of
+// Solver objects (name, path, arch, version). A missing or invalid file
+// yields no solvers, rather than an error, so it can be omitted entirely.
+type ConfigRegistry struct {
+	ConfigPath string
+}
+
+// Solvers implements Registry.
+func (r ConfigRegistry) Solvers() SolverList {
+	data, err := os.ReadFile(r.ConfigPath)
+	if err != nil {
+		return nil
+	}
+
+	var entries SolverList
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil
+	}
+
+	for i := range entries {
+		if fileInfo, err := os.Stat(entries[i].Path); err == nil && entries[i].ModTime.IsZero() {
+			entries[i].ModTime = fileInfo.ModTime()
+		}
+	}
+	return entries
+}
+
+// EnvRegistry reads the RIVERCROSSING_SOLVER and RIVERCROSSING_SOLVER_DIR
+// environment variables: the former names a single solver executable
+// directly, the latter a directory holding one (either directly, or under a
+// Release subfolder, mirroring the project's usual layout).
+type EnvRegistry struct {
+	Sep string
+}
+
+// Solvers implements Registry.
+func (r EnvRegistry) Solvers() SolverList {
+	var out SolverList
+
+	if path := os.Getenv("RIVERCROSSING_SOLVER"); path != "" {
+		if fileInfo, err := os.Stat(path); err == nil {
+			out = append(out, Solver{Name: "env", Path: path, ModTime: fileInfo.ModTime()})
+		}
+	}
+
+	if dir := os.Getenv("RIVERCROSSING_SOLVER_DIR"); dir != "" {
+		for _, candidate := range []string{
+			dir + r.Sep + "RiverCrossing" + solverExt(),
+			dir + r.Sep + "Release" + r.Sep + "RiverCrossing" + solverExt(),
+		} {
+			if fileInfo, err := os.Stat(candidate); err == nil {
+				out = append(out, Solver{Name: "env-dir", Path: candidate, ModTime: fileInfo.ModTime()})
+				break
+			}
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,76 @@
+/*
+ This RiverCrossing project (https://github.com/FlorinTulba/RiverCrossing)
+ allows describing and solving River Crossing puzzles:
+  https://en.wikipedia.org/wiki/River_crossing_puzzle
+
+ (c) 2018-2025 Florin Tulba (florintulba@yahoo.com)
+*/
+
+package solverregistry
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeRegistry SolverList
+
+func (r fakeRegistry) Solvers() SolverList { return SolverList(r) }
+
+func TestMergeOverrideOrdering(t *testing.T) {
+	older := time.Unix(0, 0)
+	newer := older.Add(time.Hour)
+
+	fs := fakeRegistry{{Name: "g++-x64", Path: "/fs/RiverCrossing", ModTime: older}}
+	config := fakeRegistry{{Name: "g++-x64", Path: "/config/RiverCrossing", ModTime: newer}}
+	env := fakeRegistry{{Name: "env", Path: "/env/RiverCrossing", ModTime: newer}}
+
+	merged := Merge(fs, config, env)
+
+	g, ok := merged.Find("g++-x64")
+	if !ok {
+		t.Fatal(`expected "g++-x64" in the merged list`)
+	}
+	if g.Path != "/config/RiverCrossing" {
+		t.Fatalf("a later registry should override an earlier one's entry of the same name, got %q", g.Path)
+	}
+
+	if _, ok := merged.Find("env"); !ok {
+		t.Fatal(`expected "env" in the merged list`)
+	}
+
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 distinct names, got %d: %+v", len(merged), merged)
+	}
+}
+
+func TestSolverListNewest(t *testing.T) {
+	older := time.Unix(0, 0)
+	newer := older.Add(time.Hour)
+	list := SolverList{
+		{Name: "a", ModTime: older},
+		{Name: "b", ModTime: newer},
+	}
+
+	newest, ok := list.Newest()
+	if !ok || newest.Name != "b" {
+		t.Fatalf("Newest() = %+v, %v; want %q", newest, ok, "b")
+	}
+}
+
+func TestSolverCompiler(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/proj/x64/g++/Release/RiverCrossing", "g++"},
+		{"/proj/x64/clang++/Release/RiverCrossing", "clang++"},
+		{`C:\proj\x64\msvc\Release\RiverCrossing.exe`, "msvc"},
+		{"/custom/path/RiverCrossing", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := (Solver{Path: tt.path}).Compiler(); got != tt.want {
+			t.Errorf("Compiler() for %q = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}